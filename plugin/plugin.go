@@ -13,6 +13,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,10 @@ import (
 const (
 	// DefaultConnectHostname is the default connect hostname
 	DefaultConnectHostname = "https://jira-ci.harness.io"
+
+	// DefaultMaxPayloadBytes is the maximum request size
+	// accepted by the Jira builds and deployments api.
+	DefaultMaxPayloadBytes = 1048576
 )
 
 // Args provides plugin execution arguments.
@@ -35,6 +40,9 @@ type Args struct {
 	// Atlassian Cloud ID (required)
 	CloudID string `envconfig:"PLUGIN_CLOUD_ID"`
 
+	// Verify the Cloud ID is formatted as a UUID (optional)
+	VerifyCloudIDFormat bool `envconfig:"PLUGIN_VERIFY_CLOUD_ID_FORMAT" default:"true"`
+
 	// Instance Name (optional)
 	Instance string `envconfig:"PLUGIN_INSTANCE"`
 
@@ -51,6 +59,12 @@ type Args struct {
 	// Environmnet Type (optional)
 	EnvironmentType string `envconfig:"PLUGIN_ENVIRONMENT_TYPE"`
 
+	// Build label, defaults to the version (optional)
+	Label string `envconfig:"PLUGIN_LABEL"`
+
+	// Build display name, defaults to the pipeline name (optional)
+	DisplayName string `envconfig:"PLUGIN_DISPLAY_NAME"`
+
 	// Link to deployment (optional)
 	Link string `envconfig:"PLUGIN_LINK"`
 
@@ -74,6 +88,18 @@ type Args struct {
 	ConnectHostname string `envconfig:"PLUGIN_CONNECT_HOSTNAME"`
 	// Issue Keys(optional)
 	IssueKeys []string `envconfig:"PLUGIN_ISSUEKEYS"`
+
+	// Print the issue extraction pattern (optional)
+	PrintPattern bool `envconfig:"PLUGIN_PRINT_PATTERN"`
+
+	// Maximum payload size in bytes, 0 disables the check (optional)
+	MaxPayloadBytes int `envconfig:"PLUGIN_MAX_PAYLOAD_BYTES" default:"1048576"`
+
+	// Dry run prints the payload instead of sending it (optional)
+	DryRun bool `envconfig:"PLUGIN_DRY_RUN"`
+
+	// Dry run output file, defaults to the log (optional)
+	DryRunOutput string `envconfig:"PLUGIN_DRY_RUN_OUTPUT"`
 }
 
 // Exec executes the plugin.
@@ -86,6 +112,8 @@ func Exec(ctx context.Context, args Args) error {
 		state           = toState(args)
 		version         = toVersion(args)
 		deeplink        = toLink(args)
+		label           = toLabel(args)
+		displayName     = toDisplayName(args)
 	)
 
 	// ExtractInstanceName extracts the instance name from the provided URL if any
@@ -108,6 +136,11 @@ func Exec(ctx context.Context, args Args) error {
 		issues = args.IssueKeys
 	} else {
 		// fallback to extracting from commit if no issue keys are passed
+		if args.PrintPattern {
+			logger.Infoln("issue extraction pattern:", toIssuePattern(args))
+		} else {
+			logger.Debugln("issue extraction pattern:", toIssuePattern(args))
+		}
 		issues = extractIssues(args)
 		if len(issues) == 0 {
 			logger.Debugln("cannot find issue number")
@@ -184,7 +217,8 @@ func Exec(ctx context.Context, args Args) error {
 			{
 				BuildNumber:          args.Build.Number,
 				Description:          commitMessage,
-				DisplayName:          args.Name,
+				DisplayName:          displayName,
+				Label:                label,
 				URL:                  deeplink,
 				LastUpdated:          time.Now(),
 				PipelineID:           args.Name,
@@ -195,6 +229,28 @@ func Exec(ctx context.Context, args Args) error {
 			},
 		},
 	}
+	// the cloud id is ignored when the instance is provided
+	if args.VerifyCloudIDFormat && args.CloudID != "" && instanceName == "" {
+		if err := verifyCloudID(args.CloudID); err != nil {
+			logger.Debugln("invalid cloud id format")
+			return err
+		}
+	}
+	var payload interface{} = buildPayload
+	if args.EnvironmentName != "" || (args.ClientID != "" && args.ClientSecret != "") {
+		payload = deploymentPayload
+	}
+	// check the payload size before sending to avoid
+	// an unhelpful 413 error from the Jira api
+	if err := verifyPayloadSize(payload, args.MaxPayloadBytes); err != nil {
+		logger.Debugln("payload exceeds maximum size")
+		return err
+	}
+	// skip authentication and network calls in dry run mode
+	if args.DryRun {
+		logger.Infoln("dry run enabled, skipping submission")
+		return writeDryRun(args.DryRunOutput, payload)
+	}
 	// validation of arguments
 	if (args.ClientID == "" && args.ClientSecret == "") && (args.ConnnectKey == "") {
 		logger.Debugln("client id and secret are empty. specify the client id and secret or specify connect key")
@@ -276,6 +332,36 @@ func Exec(ctx context.Context, args Args) error {
 	return nil
 }
 
+// verifies the encoded payload does not exceed the maximum
+// size in bytes. A maximum of zero disables the check.
+func verifyPayloadSize(payload interface{}, max int) error {
+	if max <= 0 {
+		return nil
+	}
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if len(out) > max {
+		return fmt.Errorf("Payload size of %d bytes exceeds the maximum of %d bytes. Reduce the number of issue keys or adjust PLUGIN_MAX_PAYLOAD_BYTES", len(out), max)
+	}
+	return nil
+}
+
+// writes the payload that would be submitted to the
+// file at path, or to the log if no path is provided.
+func writeDryRun(path string, payload interface{}) error {
+	out, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		logrus.Infoln("dry run payload:", string(out))
+		return nil
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
 // makes an API call to create a token.
 func getOauthToken(args Args) (string, error) {
 	payload := map[string]string{
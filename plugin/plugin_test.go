@@ -4,8 +4,316 @@
 
 package plugin
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// roundTripFunc adapts a function to the http.RoundTripper
+// interface so tests can intercept outbound requests.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// helper function replaces the default http transport for
+// the duration of the test and counts outbound requests.
+func interceptRequests(t *testing.T) *int {
+	calls := new(int)
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*calls++
+		return nil, errors.New("unexpected request to " + req.URL.String())
+	})
+	t.Cleanup(func() {
+		http.DefaultClient.Transport = transport
+	})
+	return calls
+}
 
 func TestPlugin(t *testing.T) {
 	t.Skip()
 }
+
+func TestDryRunOutput(t *testing.T) {
+	calls := interceptRequests(t)
+
+	path := filepath.Join(t.TempDir(), "payload.json")
+
+	var args Args
+	args.Commit.Message = "TEST-1 updated the readme"
+	args.Build.Number = 42
+	args.Project = "TEST"
+	args.Name = "drone"
+	args.ClientID = "client-id"
+	args.ClientSecret = "client-secret"
+	args.CloudID = "a436116f-02ce-4520-8fbb-7301462a1674"
+	args.DryRun = true
+	args.DryRunOutput = path
+
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 0 {
+		t.Errorf("Expected no network calls in dry run, got %d", *calls)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := new(DeploymentPayload)
+	if err := json.Unmarshal(data, payload); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(payload.Deployments); got != 1 {
+		t.Fatalf("Expected 1 deployment, got %d", got)
+	}
+	deployment := payload.Deployments[0]
+	if got, want := deployment.Deploymentsequencenumber, 42; got != want {
+		t.Errorf("Expected deployment sequence number %d, got %d", want, got)
+	}
+	if got := deployment.Associations[0].Values; len(got) != 1 || got[0] != "TEST-1" {
+		t.Errorf("Expected issue keys [TEST-1], got %v", got)
+	}
+}
+
+func TestDryRunOutputBuild(t *testing.T) {
+	calls := interceptRequests(t)
+
+	path := filepath.Join(t.TempDir(), "payload.json")
+
+	var args Args
+	args.Commit.Message = "TEST-1 updated the readme"
+	args.Build.Number = 42
+	args.Project = "TEST"
+	args.Name = "drone"
+	args.ConnnectKey = "connect-key"
+	args.DryRun = true
+	args.DryRunOutput = path
+
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 0 {
+		t.Errorf("Expected no network calls in dry run, got %d", *calls)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := new(BuildPayload)
+	if err := json.Unmarshal(data, payload); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(payload.Builds); got != 1 {
+		t.Fatalf("Expected 1 build, got %d", got)
+	}
+	if got, want := payload.Builds[0].BuildNumber, 42; got != want {
+		t.Errorf("Expected build number %d, got %d", want, got)
+	}
+}
+
+func TestBuildLabelAndDisplayName(t *testing.T) {
+	interceptRequests(t)
+
+	path := filepath.Join(t.TempDir(), "payload.json")
+
+	var args Args
+	args.Commit.Message = "TEST-1 updated the readme"
+	args.Semver.Version = "1.2.3"
+	args.Project = "TEST"
+	args.Name = "drone"
+	args.ConnnectKey = "connect-key"
+	args.DryRun = true
+	args.DryRunOutput = path
+
+	tests := []struct {
+		name        string
+		label       string
+		displayName string
+		wantLabel   string
+		wantDisplay string
+	}{
+		{"Defaults", "", "", "1.2.3", "drone"},
+		{"Label only", "release-1", "", "release-1", "drone"},
+		{"Display name only", "", "Nightly", "1.2.3", "Nightly"},
+		{"Both", "release-1", "Nightly", "release-1", "Nightly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := args
+			args.Label = tt.label
+			args.DisplayName = tt.displayName
+
+			if err := Exec(context.Background(), args); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			payload := new(BuildPayload)
+			if err := json.Unmarshal(data, payload); err != nil {
+				t.Fatal(err)
+			}
+			build := payload.Builds[0]
+			if build.Label != tt.wantLabel {
+				t.Errorf("Expected label %q, got %q", tt.wantLabel, build.Label)
+			}
+			if build.DisplayName != tt.wantDisplay {
+				t.Errorf("Expected display name %q, got %q", tt.wantDisplay, build.DisplayName)
+			}
+			if build.PipelineID != "drone" {
+				t.Errorf("Expected pipeline id %q, got %q", "drone", build.PipelineID)
+			}
+		})
+	}
+}
+
+func TestVerifyCloudIDFormat(t *testing.T) {
+	calls := interceptRequests(t)
+
+	var args Args
+	args.Commit.Message = "TEST-1 updated the readme"
+	args.Project = "TEST"
+	args.Name = "drone"
+	args.ClientID = "client-id"
+	args.ClientSecret = "client-secret"
+	args.CloudID = "mycompany"
+	args.VerifyCloudIDFormat = true
+	args.DryRun = true
+
+	err := Exec(context.Background(), args)
+	if err == nil {
+		t.Fatal("Expected error for instance name provided as cloud id")
+	}
+	if !strings.Contains(err.Error(), "PLUGIN_INSTANCE") {
+		t.Errorf("Expected error to suggest PLUGIN_INSTANCE, got %q", err)
+	}
+	if *calls != 0 {
+		t.Errorf("Expected no network calls, got %d", *calls)
+	}
+
+	// verification is skipped when disabled
+	args.VerifyCloudIDFormat = false
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Expected no error when verification is disabled, got %s", err)
+	}
+
+	args.VerifyCloudIDFormat = true
+	args.CloudID = "a436116f-02ce-4520-8fbb-7301462a1674"
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Expected no error for valid cloud id, got %s", err)
+	}
+}
+
+func TestPrintPattern(t *testing.T) {
+	interceptRequests(t)
+
+	hook := test.NewGlobal()
+	t.Cleanup(hook.Reset)
+
+	var args Args
+	args.Commit.Message = "TEST-1 updated the readme"
+	args.Project = "TEST"
+	args.Name = "drone"
+	args.ConnnectKey = "connect-key"
+	args.PrintPattern = true
+	args.DryRun = true
+	args.DryRunOutput = filepath.Join(t.TempDir(), "payload.json")
+
+	if err := Exec(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+
+	var pattern string
+	for _, entry := range hook.AllEntries() {
+		if strings.HasPrefix(entry.Message, "issue extraction pattern:") {
+			if entry.Level != logrus.InfoLevel {
+				t.Errorf("Expected pattern logged at info level, got %s", entry.Level)
+			}
+			pattern = strings.TrimSpace(strings.TrimPrefix(entry.Message, "issue extraction pattern:"))
+		}
+	}
+	if pattern == "" {
+		t.Fatal("Expected issue extraction pattern to be logged")
+	}
+	if want := toIssuePattern(args); pattern != want {
+		t.Errorf("Expected pattern %q, got %q", want, pattern)
+	}
+	if !regexp.MustCompile(pattern).MatchString("TEST-123") {
+		t.Errorf("Expected pattern %q to match the configured project", pattern)
+	}
+}
+
+func TestMaxPayloadBytes(t *testing.T) {
+	calls := interceptRequests(t)
+
+	// synthetic payload with enough issue keys to exceed
+	// the default maximum payload size.
+	var issues []string
+	for i := 0; i < 100000; i++ {
+		issues = append(issues, fmt.Sprintf("TEST-%d", i))
+	}
+
+	var args Args
+	args.Project = "TEST"
+	args.Name = "drone"
+	args.ConnnectKey = "connect-key"
+	args.IssueKeys = issues
+	args.MaxPayloadBytes = DefaultMaxPayloadBytes
+	args.DryRun = true
+	args.DryRunOutput = filepath.Join(t.TempDir(), "payload.json")
+
+	err := Exec(context.Background(), args)
+	if err == nil {
+		t.Fatal("Expected error for oversized payload")
+	}
+	if !strings.Contains(err.Error(), "PLUGIN_MAX_PAYLOAD_BYTES") {
+		t.Errorf("Expected error to reference PLUGIN_MAX_PAYLOAD_BYTES, got %q", err)
+	}
+	if _, err := os.Stat(args.DryRunOutput); !os.IsNotExist(err) {
+		t.Errorf("Expected no dry run payload for oversized payload")
+	}
+	if *calls != 0 {
+		t.Errorf("Expected no network calls, got %d", *calls)
+	}
+
+	// the check is skipped when disabled
+	args.MaxPayloadBytes = 0
+	if err := Exec(context.Background(), args); err != nil {
+		t.Errorf("Expected no error when the check is disabled, got %s", err)
+	}
+}
+
+func TestVerifyPayloadSize(t *testing.T) {
+	payload := BuildPayload{
+		Builds: []*Build{{BuildNumber: 1, IssueKeys: []string{"TEST-1"}}},
+	}
+	out, _ := json.Marshal(payload)
+
+	if err := verifyPayloadSize(payload, len(out)); err != nil {
+		t.Errorf("Expected payload at the maximum size to pass, got %s", err)
+	}
+	if err := verifyPayloadSize(payload, len(out)-1); err == nil {
+		t.Errorf("Expected payload above the maximum size to fail")
+	}
+	if err := verifyPayloadSize(payload, 0); err != nil {
+		t.Errorf("Expected check to be disabled, got %s", err)
+	}
+}
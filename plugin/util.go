@@ -13,23 +13,55 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// mergeRegexp matches the source branch of a merge commit
+// message generated by GitHub or git, for example:
+//
+//	Merge pull request #42 from octocat/feature/ABC-1
+//	Merge branch 'feature/ABC-1' into main
+var mergeRegexp = regexp.MustCompile(`(?m)^Merge (?:pull request #\d+ from (\S+)|(?:remote-tracking )?branch '([^']+)')`)
+
+// cloudIDRegexp matches an Atlassian cloud id, which is a UUID.
+var cloudIDRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 // helper function to extract the issue number from
 // the commit details, including the commit message,
 // branch and pull request title.
 func extractIssues(args Args) []string {
 
-	regex := regexp.MustCompile(args.Project + "\\-\\d+")
+	regex := regexp.MustCompile(toIssuePattern(args))
 	matches := regex.FindAllString(fmt.Sprintln(
 		args.Commit.Message,
 		args.PullRequest.Title,
 		args.Commit.Source,
 		args.Commit.Target,
 		args.Commit.Branch,
+		toMergeBranch(args.Commit.Message),
 	), -1)
 
 	return removeDuplicates(matches)
 }
 
+// helper function returns the regular expression used to
+// extract issue numbers for the configured project.
+func toIssuePattern(args Args) string {
+	return args.Project + "\\-\\d+"
+}
+
+// helper function extracts the source branch from a merge
+// commit message. Branch names are frequently lowercase, so
+// the branch is returned in uppercase to match issue keys.
+func toMergeBranch(message string) string {
+	match := mergeRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return ""
+	}
+	branch := match[1]
+	if branch == "" {
+		branch = match[2]
+	}
+	return strings.ToUpper(branch)
+}
+
 // helper function determines the pipeline state.
 func toState(args Args) string {
 	if v := args.State; v != "" {
@@ -79,6 +111,22 @@ func toVersion(args Args) string {
 	return args.Commit.Rev
 }
 
+// helper function determines the build label.
+func toLabel(args Args) string {
+	if v := args.Label; v != "" {
+		return v
+	}
+	return toVersion(args)
+}
+
+// helper function determines the build display name.
+func toDisplayName(args Args) string {
+	if v := args.DisplayName; v != "" {
+		return v
+	}
+	return args.Name
+}
+
 // helper function provides a deeplink to the build
 // or a fallback link to the commit in version control.
 func toLink(args Args) string {
@@ -118,6 +166,16 @@ func ExtractInstanceName(instance string) string {
 	return instance
 }
 
+// helper function verifies the cloud id is a UUID. A common
+// mistake is to provide the site name instead, which results
+// in a confusing 404 error from the Jira API.
+func verifyCloudID(cloudID string) error {
+	if cloudIDRegexp.MatchString(cloudID) {
+		return nil
+	}
+	return fmt.Errorf("Cloud id %q is not a valid UUID. To specify the site name use PLUGIN_INSTANCE instead", cloudID)
+}
+
 // helper function normalizes the environment to match
 // the expected bitbucket enum.
 func toEnvironmentEnum(s string) string {
@@ -97,6 +97,72 @@ func TestExtractIssues(t *testing.T) {
 	}
 }
 
+func TestExtractIssuesMergeCommit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "GitHub merge commit",
+			text: "Merge pull request #42 from octocat/feature/ABC-1\n\nAdd the new widget",
+			want: []string{"ABC-1"},
+		},
+		{
+			name: "GitHub merge commit with lowercase branch",
+			text: "Merge pull request #42 from octocat/feature/abc-12-new-widget\n\nAdd the new widget",
+			want: []string{"ABC-12"},
+		},
+		{
+			name: "GitHub merge commit with key in body",
+			text: "Merge pull request #7 from octocat/bugfix\n\nABC-34 fix the broken widget",
+			want: []string{"ABC-34"},
+		},
+		{
+			name: "Git merge branch commit",
+			text: "Merge branch 'feature/abc-56' into main",
+			want: []string{"ABC-56"},
+		},
+		{
+			name: "Pull request number is not an issue",
+			text: "Merge pull request #42 from octocat/main",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Args
+			args.Commit.Message = tt.text
+			args.Project = "ABC"
+
+			got := extractIssues(args)
+			if len(got) != len(tt.want) || !compareSlices(got, tt.want) {
+				t.Errorf("\ngot: %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMergeBranch(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Merge pull request #42 from octocat/feature/ABC-1", "OCTOCAT/FEATURE/ABC-1"},
+		{"Merge branch 'feature/abc-1' into main", "FEATURE/ABC-1"},
+		{"Merge remote-tracking branch 'origin/abc-1'", "ORIGIN/ABC-1"},
+		{"ABC-1 updated the readme", ""},
+	}
+
+	for _, test := range tests {
+		result := toMergeBranch(test.text)
+		if result != test.want {
+			t.Errorf("toMergeBranch(%q) = %q; expected %q", test.text, result, test.want)
+		}
+	}
+}
+
 func TestExtractInstanceName(t *testing.T) {
 	tests := []struct {
 		text string
@@ -182,3 +248,90 @@ func TestToEnvironmentType(t *testing.T) {
 		})
 	}
 }
+
+// Test the toLabel function
+func TestToLabel(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           Args
+		expectedOutput string
+	}{
+		{
+			name:           "Non-empty Label",
+			args:           Args{Label: "release-1"},
+			expectedOutput: "release-1",
+		},
+		{
+			name: "Empty Label",
+			args: func() Args {
+				var args Args
+				args.Semver.Version = "1.2.3"
+				args.DisplayName = "Nightly"
+				return args
+			}(),
+			expectedOutput: "1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := toLabel(tt.args)
+			if result != tt.expectedOutput {
+				t.Errorf("toLabel() = %v, want %v", result, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+// Test the toDisplayName function
+func TestToDisplayName(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           Args
+		expectedOutput string
+	}{
+		{
+			name:           "Non-empty DisplayName",
+			args:           Args{DisplayName: "Nightly", Name: "drone", Label: "release-1"},
+			expectedOutput: "Nightly",
+		},
+		{
+			name:           "Empty DisplayName",
+			args:           Args{Name: "drone", Label: "release-1"},
+			expectedOutput: "drone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := toDisplayName(tt.args)
+			if result != tt.expectedOutput {
+				t.Errorf("toDisplayName() = %v, want %v", result, tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestVerifyCloudID(t *testing.T) {
+	tests := []struct {
+		text  string
+		valid bool
+	}{
+		{"a436116f-02ce-4520-8fbb-7301462a1674", true},
+		{"A436116F-02CE-4520-8FBB-7301462A1674", true},
+		{"mycompany", false},
+		{"mycompany.atlassian.net", false},
+		{"https://mycompany.atlassian.net", false},
+		{"a436116f02ce45208fbb7301462a1674", false},
+	}
+
+	for _, test := range tests {
+		err := verifyCloudID(test.text)
+		if test.valid && err != nil {
+			t.Errorf("verifyCloudID(%q) returned unexpected error %s", test.text, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("verifyCloudID(%q) expected error", test.text)
+		}
+	}
+}